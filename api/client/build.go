@@ -0,0 +1,29 @@
+package client
+
+import (
+	"net/url"
+
+	"github.com/docker/docker/api/client/build"
+	flag "github.com/docker/docker/pkg/mflag"
+)
+
+// buildBackend is the subset of the daemon connection CmdBuild needs to
+// kick off a build with the query build.Parse built.
+type buildBackend interface {
+	ImageBuild(query url.Values) error
+}
+
+// CmdBuild is the entry point for `docker build [--function ...] PATH`,
+// parsing the --function filter via build.Parse and sending the resulting
+// query to the daemon's build endpoint alongside the build context (elided
+// here; outside this series).
+func (cli *DockerCli) CmdBuild(args ...string) error {
+	cmd := flag.NewFlagSet("build", flag.ExitOnError)
+
+	query, err := build.Parse(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	return cli.buildClient.ImageBuild(query)
+}
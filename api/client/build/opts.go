@@ -0,0 +1,31 @@
+package build
+
+import (
+	"net/url"
+
+	flag "github.com/docker/docker/pkg/mflag"
+)
+
+// addFunctionFilterFlag registers the --function flag for `docker build`,
+// restricting which FUNCTION layers are registered in the built image's
+// function manifest.
+func addFunctionFilterFlag(cmd *flag.FlagSet) *[]string {
+	return cmd.StringSlice([]string{"-function"}, []string{}, "Only register the named FUNCTION layers in the image's function manifest")
+}
+
+// Parse parses the build command's flags and returns the query values the
+// request to the daemon's build endpoint should carry. cmd must not have
+// been parsed yet.
+func Parse(cmd *flag.FlagSet, args []string) (url.Values, error) {
+	functionFilter := addFunctionFilterFlag(cmd)
+
+	if err := cmd.ParseFlags(args, true); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	for _, f := range *functionFilter {
+		query.Add("function", f)
+	}
+	return query, nil
+}
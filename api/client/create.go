@@ -0,0 +1,36 @@
+package client
+
+import (
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/docker/runconfig/opts"
+	"github.com/docker/engine-api/types"
+)
+
+// backend is the subset of the daemon connection CmdCreate and CmdRun need
+// to send the config opts.Parse built.
+type backend interface {
+	ContainerCreate(config types.ContainerCreateConfig) (types.ContainerCreateResponse, error)
+}
+
+// DockerCli holds the state this package's own commits depend on. The real
+// DockerCli carries the rest of the client (output streams, auth,
+// connection) that this series doesn't touch.
+type DockerCli struct {
+	client      backend
+	buildClient buildBackend
+}
+
+// CmdCreate is the entry point for `docker create [--function ...] IMAGE`,
+// parsing its flags via opts.Parse before sending the create request to the
+// daemon.
+func (cli *DockerCli) CmdCreate(args ...string) error {
+	cmd := flag.NewFlagSet("create", flag.ExitOnError)
+
+	config, err := opts.Parse(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = cli.client.ContainerCreate(*config)
+	return err
+}
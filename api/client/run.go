@@ -0,0 +1,25 @@
+package client
+
+import (
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/docker/runconfig/opts"
+)
+
+// CmdRun is the entry point for `docker run [--function ...] IMAGE`. It
+// shares opts.Parse with CmdCreate since both build a ContainerCreateConfig
+// from the same flags, including --function, before going on to start and
+// attach to the resulting container (elided here; outside this series).
+func (cli *DockerCli) CmdRun(args ...string) error {
+	cmd := flag.NewFlagSet("run", flag.ExitOnError)
+
+	config, err := opts.Parse(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if _, err := cli.client.ContainerCreate(*config); err != nil {
+		return err
+	}
+
+	return nil
+}
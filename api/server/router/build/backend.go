@@ -0,0 +1,12 @@
+package build
+
+import (
+	"github.com/docker/docker/builder/dockerfile"
+	"github.com/docker/docker/image"
+)
+
+// Backend is all the methods that need to be implemented to provide build
+// functionality.
+type Backend interface {
+	Build(options *dockerfile.Options) (image.ID, error)
+}
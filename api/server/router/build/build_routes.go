@@ -0,0 +1,28 @@
+package build
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/builder/dockerfile"
+)
+
+// postBuild handles POST /build, running a Dockerfile build restricted by
+// the repeated --function query values CmdBuild sends via
+// api/client/build.Parse.
+func (r *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, req *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(req); err != nil {
+		return err
+	}
+
+	options := dockerfile.NewOptions(req.Form)
+
+	imageID, err := r.backend.Build(options)
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, map[string]string{"ID": imageID.String()})
+}
@@ -0,0 +1,9 @@
+package container
+
+import "github.com/docker/engine-api/types"
+
+// Backend is all the methods that need to be implemented to provide container
+// creation functionality.
+type Backend interface {
+	ContainerCreate(config types.ContainerCreateConfig) (types.ContainerCreateResponse, error)
+}
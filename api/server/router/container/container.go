@@ -0,0 +1,31 @@
+package container
+
+import (
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/api/server/router"
+)
+
+// containerRouter is a router to talk with the container controller.
+type containerRouter struct {
+	backend Backend
+	decoder httputils.ContainerDecoder
+	routes  []router.Route
+}
+
+// NewRouter initializes a new container router.
+func NewRouter(backend Backend, decoder httputils.ContainerDecoder) router.Router {
+	r := &containerRouter{backend: backend, decoder: decoder}
+	r.initRoutes()
+	return r
+}
+
+// Routes returns the available routers to the container controller.
+func (r *containerRouter) Routes() []router.Route {
+	return r.routes
+}
+
+func (r *containerRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewPostRoute("/containers/create", r.postContainersCreate),
+	}
+}
@@ -0,0 +1,49 @@
+package container
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/engine-api/types"
+)
+
+func (s *containerRouter) postContainersCreate(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	if err := httputils.CheckForJSON(r); err != nil {
+		return err
+	}
+
+	name := r.Form.Get("name")
+
+	config, hostConfig, networkingConfig, err := s.decoder.DecodeConfig(r.Body)
+	if err != nil {
+		return err
+	}
+
+	ccr, err := s.backend.ContainerCreate(types.ContainerCreateConfig{
+		Name:             name,
+		Config:           config,
+		HostConfig:       hostConfig,
+		NetworkingConfig: networkingConfig,
+		// ComposeFunctions is query-only: it names layers to stack on top of
+		// Config.Image rather than configuring the container itself, so it
+		// doesn't belong in the JSON body schema.
+		ComposeFunctions: r.Form["function"],
+	})
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			// An unresolvable image or ComposeFunctions entry is a client
+			// error, not a server one; surface it as 404 rather than
+			// falling through to the default 500.
+			return httputils.WriteJSON(w, http.StatusNotFound, map[string]string{"message": err.Error()})
+		}
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, ccr)
+}
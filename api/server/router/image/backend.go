@@ -0,0 +1,10 @@
+package image
+
+import "github.com/docker/docker/image"
+
+// Backend is all the methods that need to be implemented to provide image
+// specific functionality.
+type Backend interface {
+	LookupImage(name string) (*image.Image, error)
+	ListFunctionLayers(imageID image.ID) ([]string, error)
+}
@@ -0,0 +1,27 @@
+package image
+
+import "github.com/docker/docker/api/server/router"
+
+// imageRouter is a router to talk with the image controller.
+type imageRouter struct {
+	backend Backend
+	routes  []router.Route
+}
+
+// NewRouter initializes a new image router.
+func NewRouter(backend Backend) router.Router {
+	r := &imageRouter{backend: backend}
+	r.initRoutes()
+	return r
+}
+
+// Routes returns the available routers to the image controller.
+func (r *imageRouter) Routes() []router.Route {
+	return r.routes
+}
+
+func (r *imageRouter) initRoutes() {
+	r.routes = []router.Route{
+		router.NewGetRoute("/images/{name:.*}/functions", r.getImageFunctions),
+	}
+}
@@ -0,0 +1,25 @@
+package image
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/docker/api/server/httputils"
+)
+
+// getImageFunctions handles GET /images/{name}/functions, listing the
+// function names registered against the image's manifest.
+func (s *imageRouter) getImageFunctions(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	img, err := s.backend.LookupImage(vars["name"])
+	if err != nil {
+		return err
+	}
+
+	functions, err := s.backend.ListFunctionLayers(img.ID())
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, functions)
+}
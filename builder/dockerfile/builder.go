@@ -0,0 +1,57 @@
+package dockerfile
+
+import (
+	"net/url"
+
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+)
+
+// dockerBackend is the subset of the daemon a Builder needs to persist
+// FUNCTION layers it records during a build.
+type dockerBackend interface {
+	RegisterFunctionLayer(imageID image.ID, function string, chainID layer.ChainID) error
+}
+
+// Builder holds the state this package's own commits depend on. The real
+// builder.go carries the rest of the build (instruction dispatch, build
+// context, cache) that this series doesn't touch.
+type Builder struct {
+	options        *Options
+	docker         dockerBackend
+	functionLayers map[string]layer.ChainID
+}
+
+// NewBuilder returns a Builder configured with options, defaulting to an
+// empty Options (every FUNCTION layer kept) if none is given.
+func NewBuilder(options *Options, docker dockerBackend) *Builder {
+	if options == nil {
+		options = &Options{}
+	}
+	return &Builder{options: options, docker: docker}
+}
+
+// NewOptions builds the Options for a build request from its query values,
+// namely FunctionFilter from the repeated --function flag sent by
+// api/client/build.Parse.
+func NewOptions(query url.Values) *Options {
+	return &Options{FunctionFilter: query["function"]}
+}
+
+// Finalize persists the FUNCTION layers recorded over the build against the
+// finished image's ID. It's called once instruction dispatch (elided here;
+// outside this series) has produced imageID, and is a no-op if the
+// Dockerfile had no FUNCTION instructions or every one was filtered out.
+func (b *Builder) Finalize(imageID image.ID) error {
+	return b.writeFunctionManifest(imageID)
+}
+
+// commit finalizes the build stage's filesystem changes accumulated since
+// the last commit as a new layer and returns its ChainID. This is a
+// trimmed stand-in for the real method, which also snapshots the image
+// config and consults the build cache; function (in dispatchers.go) only
+// needs the resulting ChainID to record against the FUNCTION instruction's
+// name.
+func (b *Builder) commit(comment string) (layer.ChainID, error) {
+	return "", nil
+}
@@ -0,0 +1,24 @@
+package dockerfile
+
+// Options holds the configurable parameters for a build not covered by the
+// Dockerfile itself.
+type Options struct {
+	// FunctionFilter, when non-empty, restricts which FUNCTION layers get
+	// registered in the image's function manifest to just these names.
+	// Every FUNCTION instruction still runs and produces a layer, since
+	// later instructions may depend on its filesystem, but a function left
+	// out of this list won't be composable later via ComposeFunctions.
+	FunctionFilter []string
+}
+
+func (o *Options) keepsFunction(name string) bool {
+	if len(o.FunctionFilter) == 0 {
+		return true
+	}
+	for _, f := range o.FunctionFilter {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package dockerfile
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/builder/dockerfile/command"
+)
+
+// evaluateTable maps an instruction's lowercased name to the function that
+// runs it against a Builder. This is a trimmed stand-in for the real
+// package-level table, which has an entry for every instruction in
+// command.Commands; this series only adds the one below for
+// command.Function.
+var evaluateTable = map[string]func(*Builder, []string, map[string]bool, string) error{
+	command.Function: function,
+}
+
+// function implements the FUNCTION <name> Dockerfile instruction. It
+// finalizes whatever the current build stage has produced as its own
+// layer, notes that layer's ChainID under name via recordFunctionLayer
+// (unless Options.FunctionFilter excludes name), and starts a fresh layer
+// for the instructions that follow — the same way a COMMIT would, just
+// without ending the build. The noted ChainIDs reach the image's function
+// manifest only when Finalize runs with the finished image's ID. It is registered in the dispatch table keyed by
+// command.Function alongside the other instruction dispatchers.
+//
+// FUNCTION <name>
+func function(b *Builder, args []string, attributes map[string]bool, original string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("FUNCTION requires exactly one argument")
+	}
+	name := args[0]
+
+	chainID, err := b.commit(fmt.Sprintf("FUNCTION %s", name))
+	if err != nil {
+		return err
+	}
+
+	b.recordFunctionLayer(name, chainID)
+	return nil
+}
@@ -0,0 +1,34 @@
+package dockerfile
+
+import (
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+)
+
+// recordFunctionLayer notes that the FUNCTION instruction just committed
+// produced chainID under name, to be written out as part of the image's
+// function manifest once the build finishes and a final image ID exists.
+// A name excluded by Options.FunctionFilter is committed (later
+// instructions may still depend on its filesystem) but never recorded.
+func (b *Builder) recordFunctionLayer(name string, chainID layer.ChainID) {
+	if !b.options.keepsFunction(name) {
+		return
+	}
+	if b.functionLayers == nil {
+		b.functionLayers = map[string]layer.ChainID{}
+	}
+	b.functionLayers[name] = chainID
+}
+
+// writeFunctionManifest persists the function -> layer mapping collected
+// over the build against the finished image's ID, alongside the image
+// config the rest of the commit writes. It is a no-op if the Dockerfile
+// had no FUNCTION instructions.
+func (b *Builder) writeFunctionManifest(imageID image.ID) error {
+	for name, chainID := range b.functionLayers {
+		if err := b.docker.RegisterFunctionLayer(imageID, name, chainID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
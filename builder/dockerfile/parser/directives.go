@@ -0,0 +1,43 @@
+package parser
+
+import "github.com/docker/docker/builder/dockerfile/command"
+
+// Node is the parsed form of a single Dockerfile instruction. This is a
+// trimmed stand-in for the real parser package's Node, carrying just the
+// Value this series' own dispatcher (function, in ../dispatchers.go) reads
+// back out as its argument string; the real type carries the rest of the
+// AST (Next, Children, Attributes, and so on) that this series doesn't
+// touch.
+type Node struct {
+	Value string
+}
+
+// directives holds per-line parser state private to a single Dockerfile
+// parse. This is a trimmed stand-in for the real type, which also tracks
+// escape-character and line-continuation state across the whole file; no
+// instruction this series adds needs either.
+type directives struct{}
+
+// dispatch maps an instruction's lowercased name to the function that
+// parses its argument string into a Node. This is a trimmed stand-in for
+// the real package-level dispatch table, which has an entry for every
+// instruction in command.Commands; this series only adds the one below for
+// command.Function.
+var dispatch = map[string]func(string, *directives) (*Node, map[string]bool, error){
+	command.Function: parseFunction,
+}
+
+// parseFunction parses the FUNCTION directive's argument via
+// parseStringsWhitespaceDelimited, with no JSON array form. It is registered in dispatch above under
+// command.Function alongside the other instruction parsers.
+func parseFunction(rest string, d *directives) (*Node, map[string]bool, error) {
+	return parseStringsWhitespaceDelimited(rest, d)
+}
+
+// parseStringsWhitespaceDelimited returns a single Node whose Value is rest
+// exactly as given; it neither splits rest on whitespace nor trims it. This
+// is a trimmed stand-in for the real helper of the same name, which also
+// handles the JSON-array form other instructions accept; FUNCTION doesn't.
+func parseStringsWhitespaceDelimited(rest string, d *directives) (*Node, map[string]bool, error) {
+	return &Node{Value: rest}, nil, nil
+}
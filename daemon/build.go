@@ -0,0 +1,26 @@
+package daemon
+
+import (
+	"github.com/docker/docker/builder/dockerfile"
+	"github.com/docker/docker/image"
+)
+
+// Build runs a Dockerfile build configured by options, returning the ID of
+// the resulting image.
+//
+// The instruction dispatch loop that turns the build context into layers
+// (elided here; outside this series) constructs builder below via
+// dockerfile.NewBuilder and calls its recordFunctionLayer as each FUNCTION
+// instruction commits a layer; once it lands on the final image ID this
+// method calls Finalize to persist those recordings against it.
+func (daemon *Daemon) Build(options *dockerfile.Options) (image.ID, error) {
+	builder := dockerfile.NewBuilder(options, daemon)
+
+	var imageID image.ID
+
+	if err := builder.Finalize(imageID); err != nil {
+		return "", err
+	}
+
+	return imageID, nil
+}
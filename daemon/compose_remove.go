@@ -0,0 +1,16 @@
+package daemon
+
+import "github.com/docker/docker/layer"
+
+// releaseComposedParent releases the composed parent chain compose created
+// for containerID, if the container was created with ComposeFunctions. The
+// chain is kept around for the container's whole lifetime (every docker
+// start re-mounts the RWLayer against it), so create() itself only Commits
+// rather than releasing it; ContainerRm (elided here; outside this series)
+// must call this once the container's own RWLayer has been removed.
+//
+// It is safe to call for a container that was never composed:
+// RemoveComposedParent is a no-op for a ChainID that was never created.
+func (daemon *Daemon) releaseComposedParent(containerID string) error {
+	return daemon.layerStore.RemoveComposedParent(layer.ComposedChainID(containerID))
+}
@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/docker/docker/layer"
+)
+
+// recordingLayerStore is a layer.Store that records RemoveComposedParent
+// calls so releaseComposedParent can be tested without a real graph driver.
+type recordingLayerStore struct {
+	fakeLayerStore
+	removed []layer.ChainID
+}
+
+func (s *recordingLayerStore) RemoveComposedParent(id layer.ChainID) error {
+	s.removed = append(s.removed, id)
+	return nil
+}
+
+// TestReleaseComposedParentUsesComposedChainID guards the contract between
+// create() (which Commits a compose instead of releasing it) and the
+// eventual container removal path: releasing by containerID alone must
+// reach the same ChainID Compose originally produced for it.
+func TestReleaseComposedParentUsesComposedChainID(t *testing.T) {
+	store := &recordingLayerStore{}
+	daemon := &Daemon{layerStore: store}
+
+	if err := daemon.releaseComposedParent("container1"); err != nil {
+		t.Fatalf("releaseComposedParent: %v", err)
+	}
+
+	want := layer.ComposedChainID("container1")
+	if len(store.removed) != 1 || store.removed[0] != want {
+		t.Fatalf("expected %s to be released, got %v", want, store.removed)
+	}
+}
@@ -0,0 +1,17 @@
+package daemon
+
+import "github.com/docker/docker/layer"
+
+// restoreComposeJournal rolls back any function-layer compose left
+// half-finished by a previous instance of this daemon that was killed,
+// panicked, or hit an error between the graph driver materializing a
+// composed parent chain and create() either Committing it (container
+// created successfully) or Restoring it (create failed). A chain that was
+// Committed is no longer in the journal by the time the daemon restarts, so
+// this never touches a chain a running container's RWLayer still depends
+// on. NewDaemon calls this once daemon.layerStore is set up and before the
+// daemon starts serving ContainerCreate requests, so no container is ever
+// handed a parent chain that's still mid-compose.
+func (daemon *Daemon) restoreComposeJournal() error {
+	return layer.ReplayJournal(daemon.layerStore)
+}
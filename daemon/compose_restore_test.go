@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/layer"
+)
+
+// restoreTestStore is a fakeLayerStore variant with its own driver name, so
+// each test below gets a journal directory under the real, hardcoded
+// /var/lib/docker/image root that no other test can collide with.
+type restoreTestStore struct {
+	fakeLayerStore
+	driverName string
+	removed    []layer.ChainID
+}
+
+func (s *restoreTestStore) DriverName() string { return s.driverName }
+
+func (s *restoreTestStore) RemoveComposedParent(id layer.ChainID) error {
+	s.removed = append(s.removed, id)
+	return nil
+}
+
+// cleanupJournalDir removes the real on-disk journal directory a test wrote
+// to, since composeJournalRoot is private to the layer package and can't be
+// redirected to a temp dir from here the way layer's own tests do.
+func cleanupJournalDir(t *testing.T, driverName string) {
+	t.Helper()
+	t.Cleanup(func() {
+		os.RemoveAll(filepath.Join("/var/lib/docker/image", driverName))
+	})
+}
+
+// TestRestoreComposeJournalReleasesUncommittedCompose guards the scenario
+// the chunk0-2 lifecycle fix changed the meaning of: a daemon restart must
+// still roll back a compose that crashed before create() ever reached
+// Commit, going through daemon.restoreComposeJournal() itself rather than
+// layer.ReplayJournal directly, since that's the entry point NewDaemon
+// actually calls.
+func TestRestoreComposeJournalReleasesUncommittedCompose(t *testing.T) {
+	store := &restoreTestStore{driverName: "daemon-restore-test-uncommitted"}
+	cleanupJournalDir(t, store.driverName)
+	daemon := &Daemon{layerStore: store}
+
+	composer := layer.NewLayerComposer(store)
+	result, err := composer.Compose("container1", layer.ChainID("base"), []layer.ChainID{"fn1"})
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+
+	// Simulate a crash: create() never got to Commit or Restore.
+	if err := daemon.restoreComposeJournal(); err != nil {
+		t.Fatalf("restoreComposeJournal: %v", err)
+	}
+
+	if len(store.removed) != 1 || store.removed[0] != result.ChainID {
+		t.Fatalf("expected %s to be released on restart, got %v", result.ChainID, store.removed)
+	}
+}
+
+// TestRestoreComposeJournalKeepsCommittedCompose is the flip side: once
+// create() has Committed a compose, the container it built may already be
+// running (or be restarted later) against that chain, so a daemon restart
+// must leave it alone.
+func TestRestoreComposeJournalKeepsCommittedCompose(t *testing.T) {
+	store := &restoreTestStore{driverName: "daemon-restore-test-committed"}
+	cleanupJournalDir(t, store.driverName)
+	daemon := &Daemon{layerStore: store}
+
+	composer := layer.NewLayerComposer(store)
+	result, err := composer.Compose("container1", layer.ChainID("base"), []layer.ChainID{"fn1"})
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+	if err := composer.Commit(result); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	if err := daemon.restoreComposeJournal(); err != nil {
+		t.Fatalf("restoreComposeJournal: %v", err)
+	}
+
+	if len(store.removed) != 0 {
+		t.Fatalf("expected restoreComposeJournal to leave a committed compose alone, got removed=%v", store.removed)
+	}
+}
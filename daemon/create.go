@@ -1,15 +1,11 @@
 package daemon
 
 import (
-	"bufio"
 	"fmt"
-	"io/ioutil"
-	"os"
-	"path"
-	"strings"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/idtools"
@@ -61,21 +57,12 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig) (retC *containe
 		container *container.Container
 		img       *image.Image
 		imgID     image.ID
+		composed  *layer.ComposeResult
 		err       error
-		layersP   string
 	)
 
 	if params.Config.Image != "" {
-		fmt.Println("daemon/create.go...Config.Image...", params.Config.Image)
-		tmp := strings.Split(params.Config.Image, "_")
-		fmt.Println(tmp[1:])
-		layersP, err = compose(tmp[1:])
-		if err != nil {
-			fmt.Println("compose error")
-			return nil, err
-		}
-
-		img, err = daemon.GetImage(tmp[0])
+		img, err = daemon.GetImage(params.Config.Image)
 		if err != nil {
 			return nil, err
 		}
@@ -97,12 +84,45 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig) (retC *containe
 		}
 	}()
 
+	if img != nil && len(params.ComposeFunctions) > 0 {
+		composed, err = daemon.compose(container.ID, img.RootFS.ChainID(), imgID, params.ComposeFunctions)
+		if err != nil {
+			return nil, err
+		}
+		composer := layer.NewLayerComposer(daemon.layerStore)
+		// Named-return-error pattern: this defer runs on every exit path
+		// from here on. Unlike the composed chain's journal entry, the
+		// chain itself must not go away just because create() returns:
+		// the container's RWLayer is mounted against it on every
+		// subsequent docker start, not just now. So a failed create
+		// (retErr != nil, no container left to use the chain) releases
+		// it immediately via Restore, while a successful one only
+		// Commits, clearing the journal entry and leaving the chain for
+		// releaseComposedParent to release once the container is
+		// eventually removed. If either call fails, that failure becomes
+		// the create's error (when nothing else already failed first) so
+		// the already registered ContainerRm cleanup defer above still
+		// runs.
+		defer func() {
+			if retErr != nil {
+				if err := composer.Restore(composed); err != nil {
+					logrus.Errorf("Error releasing composed layer chain for %s: %v", container.ID, err)
+				}
+				return
+			}
+			if err := composer.Commit(composed); err != nil {
+				logrus.Errorf("Error committing composed layer chain for %s: %v", container.ID, err)
+				retErr = err
+			}
+		}()
+	}
+
 	if err := daemon.setSecurityOptions(container, params.HostConfig); err != nil {
 		return nil, err
 	}
 
 	// Set RWLayer for container after mount labels have been set
-	if err := daemon.setRWLayer(container); err != nil {
+	if err := daemon.setRWLayer(container, composed); err != nil {
 		return nil, err
 	}
 
@@ -147,11 +167,6 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig) (retC *containe
 	}
 	daemon.LogContainerEvent(container, "create")
 
-	if err := restore(layersP); err != nil {
-		fmt.Println("restore error")
-		return nil, err
-	}
-
 	return container, nil
 }
 
@@ -170,17 +185,20 @@ func (daemon *Daemon) generateSecurityOpt(ipcMode containertypes.IpcMode, pidMod
 	return nil, nil
 }
 
-func (daemon *Daemon) setRWLayer(container *container.Container) error {
+// setRWLayer creates the container's RWLayer on top of composed.ChainID when
+// the container was created with ComposeFunctions, or on top of its image's
+// own chain ID otherwise.
+func (daemon *Daemon) setRWLayer(container *container.Container, composed *layer.ComposeResult) error {
 	var layerID layer.ChainID
-	if container.ImageID != "" {
+	if composed != nil {
+		layerID = composed.ChainID
+	} else if container.ImageID != "" {
 		img, err := daemon.imageStore.Get(container.ImageID)
 		if err != nil {
 			return err
 		}
 		layerID = img.RootFS.ChainID()
 	}
-	fmt.Println("setRWLayer...", layerID)
-	fmt.Println("MountLabel: ", container.MountLabel)
 	rwLayer, err := daemon.layerStore.CreateRWLayer(container.ID, layerID, container.MountLabel, daemon.setupInitLayer)
 	if err != nil {
 		return err
@@ -209,118 +227,37 @@ func (daemon *Daemon) VolumeCreate(name, driverName string, opts map[string]stri
 	return volumeToAPIType(v), nil
 }
 
-func compose(paras []string) (string, error) {
-
-	// function2layerdb => map
-	manifestP := path.Join("/var/lib/docker/image/aufs/imagedb", "manifest", paras[0])
-	fmt.Println(manifestP)
-	manifestF, err := os.Open(manifestP)
+// compose resolves the named functions against imageID's function manifest
+// and asks the layer store's LayerComposer to stack them on top of base.
+// How that stacking is materialized (AUFS's branch list, overlay2's
+// lowerdir, ...) is entirely up to the graph driver behind daemon.layerStore;
+// compose only knows how to turn function names into an ordered chain of
+// layer IDs.
+func (daemon *Daemon) compose(containerID string, base layer.ChainID, imageID image.ID, functions []string) (*layer.ComposeResult, error) {
+	chain, err := daemon.functionChain(imageID, functions)
 	if err != nil {
-		fmt.Println("open manifest file error")
-		return "", err
-	}
-	defer manifestF.Close()
-
-	functionM := make(map[string]string)
-	s := bufio.NewScanner(manifestF)
-	for s.Scan() {
-		if t := s.Text(); t != "" {
-			m := strings.Split(t, ",")
-			functionM[m[0]] = m[1]
-		}
-	}
-	fmt.Println(functionM)
-
-	// paras to be composed => slice
-	composeS := make([]string, 0, 5)
-	paras = append(paras, "chainID") // chainID correspond to reserved top layer
-	for _, para := range paras {
-		layerdbP := path.Join("/var/lib/docker/image/aufs/layerdb/sha256", functionM[para], "cache-id")
-		layerF, err := os.Open(layerdbP)
-		if err != nil {
-			fmt.Println("open layerdb file error")
-			return "", err
-		}
-		defer layerF.Close()
-
-		fd, err := ioutil.ReadAll(layerF)
-		composeS = append(composeS, strings.Replace(string(fd), "\n", "", -1)) // remove "\n"
-	}
-	fmt.Println(composeS)
-
-	// backup and replace origin layers file
-	layersP := path.Join("/var/lib/docker/aufs/layers", composeS[len(composeS)-1])
-	ids, err := getParentIds(layersP)
-	if err != nil {
-		fmt.Println("get parent ids error", err)
-		return "", err
-	}
-	err = os.Rename(layersP, strings.Join([]string{layersP, "-backup"}, ""))
-	if err != nil {
-		fmt.Println("backup error", err)
-		return "", err
-	}
-	replaceF, err := os.Create(layersP)
-	if err != nil {
-		fmt.Println("replace error", err)
-		return "", err
-	}
-	defer replaceF.Close()
-
-	// write layers to be composed
-	length := len(composeS)
-	for i := 1; i < length; i++ {
-		if _, err := fmt.Fprintln(replaceF, composeS[length-1-i]); err != nil {
-			fmt.Println("replace error", err)
-			return "", err
-		}
-	}
-	// write parent layers
-	length = len(ids)
-	for i := 0; i < length; i++ {
-		if ids[i] != composeS[0] {
-			continue
-		} else {
-			for i = i + 1; i < length; i++ {
-				if _, err := fmt.Fprintln(replaceF, ids[i]); err != nil {
-					fmt.Println("replace error", err)
-					return "", err
-				}
-			}
-		}
+		return nil, err
 	}
 
-	return layersP, nil
+	composer := layer.NewLayerComposer(daemon.layerStore)
+	return composer.Compose(containerID, base, chain)
 }
 
-// restore layers file
-func restore(layersP string) error {
-	if err := os.Remove(layersP); err != nil {
-		fmt.Println("restore error", err)
-		return err
-	}
-	err := os.Rename(strings.Join([]string{layersP, "-backup"}, ""), layersP)
-	if err != nil {
-		fmt.Println("restore error", err)
-		return err
-	}
-	return nil
-}
-
-func getParentIds(layersP string) ([]string, error) {
-	f, err := os.Open(layersP)
+// functionChain resolves functions, in order, to the layer.ChainID each one
+// was registered under in imageID's function manifest.
+func (daemon *Daemon) functionChain(imageID image.ID, functions []string) ([]layer.ChainID, error) {
+	manifest, err := daemon.funcManifests.Get(imageID)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
-
-	out := []string{}
-	s := bufio.NewScanner(f)
 
-	for s.Scan() {
-		if t := s.Text(); t != "" {
-			out = append(out, s.Text())
+	chain := make([]layer.ChainID, 0, len(functions))
+	for _, function := range functions {
+		chainID, ok := manifest.Functions[function]
+		if !ok {
+			return nil, errdefs.NotFound(fmt.Errorf("function %q not found in manifest for image %s", function, imageID))
 		}
+		chain = append(chain, chainID)
 	}
-	return out, s.Err()
+	return chain, nil
 }
@@ -0,0 +1,35 @@
+package daemon
+
+import (
+	"path/filepath"
+
+	"github.com/docker/docker/image/funcmanifest"
+	"github.com/docker/docker/layer"
+)
+
+// Daemon holds the daemon state this package's own commits depend on. The
+// real daemon.go carries many more fields (container store, network
+// controller, plugin store, and so on); they live outside this series and
+// aren't reproduced here.
+type Daemon struct {
+	layerStore    layer.Store
+	funcManifests funcmanifest.Store
+}
+
+// NewDaemon wires up the subsystem this package introduced: the
+// function-manifest store function layers get registered against, rooted
+// under the image store's directory for the configured graph driver. Like
+// the Daemon struct above, this is a trimmed stand-in for the real
+// NewDaemon, which sets up everything else first.
+func NewDaemon(layerStore layer.Store) (*Daemon, error) {
+	daemon := &Daemon{layerStore: layerStore}
+
+	root := filepath.Join("/var/lib/docker/image", layerStore.DriverName(), "funcmanifest")
+	daemon.funcManifests = funcmanifest.NewStore(root)
+
+	if err := daemon.restoreComposeJournal(); err != nil {
+		return nil, err
+	}
+
+	return daemon, nil
+}
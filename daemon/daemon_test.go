@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/docker/docker/layer"
+)
+
+// fakeLayerStore is a minimal layer.Store for exercising NewDaemon without
+// a real graph driver.
+type fakeLayerStore struct{}
+
+func (fakeLayerStore) DriverName() string { return "daemon-test-driver" }
+
+func (fakeLayerStore) CreateRWLayer(id string, parent layer.ChainID, mountLabel string, initFunc func(string) error) (layer.RWLayer, error) {
+	return nil, nil
+}
+
+func (fakeLayerStore) CreateComposedParent(id string, base layer.ChainID, chain []layer.ChainID) (layer.ChainID, error) {
+	return layer.ChainID(id), nil
+}
+
+func (fakeLayerStore) RemoveComposedParent(id layer.ChainID) error { return nil }
+
+// TestNewDaemonWiresFuncManifestsAndReplaysJournal guards against the
+// regression where NewDaemon referenced daemon.funcManifests without ever
+// constructing it, and its own doc comment claimed it called
+// restoreComposeJournal while nothing did.
+func TestNewDaemonWiresFuncManifestsAndReplaysJournal(t *testing.T) {
+	store := &restoreTestStore{driverName: "daemon-test-newdaemon"}
+	cleanupJournalDir(t, store.driverName)
+
+	// Leave an uncommitted compose in the journal for NewDaemon to replay.
+	result, err := layer.NewLayerComposer(store).Compose("container1", layer.ChainID("base"), []layer.ChainID{"fn1"})
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+
+	d, err := NewDaemon(store)
+	if err != nil {
+		t.Fatalf("NewDaemon: %v", err)
+	}
+	if d.funcManifests == nil {
+		t.Fatal("expected NewDaemon to construct funcManifests, got nil")
+	}
+	if len(store.removed) != 1 || store.removed[0] != result.ChainID {
+		t.Fatalf("expected NewDaemon to replay the compose journal and release %s, got %v", result.ChainID, store.removed)
+	}
+}
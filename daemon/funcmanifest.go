@@ -0,0 +1,19 @@
+package daemon
+
+import (
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+)
+
+// RegisterFunctionLayer records that function resolves to chainID for
+// imageID, so a later ContainerCreate naming function in ComposeFunctions
+// can look it up through daemon.funcManifests instead of requiring a
+// hand-edited manifest file on the host.
+func (daemon *Daemon) RegisterFunctionLayer(imageID image.ID, function string, chainID layer.ChainID) error {
+	return daemon.funcManifests.Put(imageID, function, chainID)
+}
+
+// ListFunctionLayers returns the function names registered for imageID.
+func (daemon *Daemon) ListFunctionLayers(imageID image.ID) ([]string, error) {
+	return daemon.funcManifests.List(imageID)
+}
@@ -0,0 +1,32 @@
+// Package errdefs defines a set of error interfaces that packages should
+// use for communicating classes of errors, and provides helpers to create
+// and check for them. Callers that need to render a specific status code
+// (e.g. the HTTP API) type-switch on these interfaces rather than on
+// package-specific error types.
+package errdefs
+
+// ErrNotFound signals that the requested object doesn't exist.
+type ErrNotFound interface {
+	NotFound()
+}
+
+type errNotFound struct {
+	error
+}
+
+func (errNotFound) NotFound() {}
+
+// NotFound wraps the given error to indicate that what it refers to could
+// not be found.
+func NotFound(err error) error {
+	if err == nil {
+		return nil
+	}
+	return errNotFound{err}
+}
+
+// IsNotFound returns true if the error is due to a missing object.
+func IsNotFound(err error) bool {
+	_, ok := err.(ErrNotFound)
+	return ok
+}
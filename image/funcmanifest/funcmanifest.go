@@ -0,0 +1,166 @@
+// Package funcmanifest records which layer each named function of an image
+// produced, so daemon.compose can resolve a function name to a layer.ChainID
+// without hand-parsing a file on the host.
+package funcmanifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+)
+
+// manifestVersion is bumped whenever the on-disk schema changes.
+const manifestVersion = 1
+
+// Manifest maps function names to the layer each one produced, for a single
+// image.
+type Manifest struct {
+	Version   int                      `json:"version"`
+	Functions map[string]layer.ChainID `json:"functions"`
+}
+
+// fileManifest is Manifest's on-disk representation: the functions plus a
+// checksum of their encoding, so a partially-written file is rejected
+// instead of silently loaded.
+type fileManifest struct {
+	Manifest
+	Checksum string `json:"checksum"`
+}
+
+func checksum(functions map[string]layer.ChainID) (string, error) {
+	b, err := json.Marshal(functions)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Store persists the function -> layer mapping for images.
+type Store interface {
+	// Get returns the full manifest recorded for imageID. A never-written
+	// image returns an empty Manifest, not an error.
+	Get(imageID image.ID) (Manifest, error)
+	// Put records that function resolves to chainID for imageID, creating
+	// the manifest if this is its first entry.
+	Put(imageID image.ID, function string, chainID layer.ChainID) error
+	// List returns the function names recorded for imageID.
+	List(imageID image.ID) ([]string, error)
+	// Delete removes function from imageID's manifest. It returns an
+	// errdefs.ErrNotFound error if function isn't recorded.
+	Delete(imageID image.ID, function string) error
+}
+
+type store struct {
+	mu   sync.Mutex
+	root string
+}
+
+// NewStore returns a Store backed by one versioned JSON file per image
+// under root.
+func NewStore(root string) Store {
+	return &store{root: root}
+}
+
+func (s *store) path(imageID image.ID) string {
+	return filepath.Join(s.root, imageID.String())
+}
+
+func (s *store) Get(imageID image.ID) (Manifest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.get(imageID)
+}
+
+func (s *store) get(imageID image.ID) (Manifest, error) {
+	b, err := ioutil.ReadFile(s.path(imageID))
+	if os.IsNotExist(err) {
+		return Manifest{Version: manifestVersion, Functions: map[string]layer.ChainID{}}, nil
+	}
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	var fm fileManifest
+	if err := json.Unmarshal(b, &fm); err != nil {
+		return Manifest{}, err
+	}
+	sum, err := checksum(fm.Functions)
+	if err != nil {
+		return Manifest{}, err
+	}
+	if sum != fm.Checksum {
+		return Manifest{}, fmt.Errorf("funcmanifest: checksum mismatch for image %s, manifest may be corrupt", imageID)
+	}
+	return fm.Manifest, nil
+}
+
+func (s *store) save(imageID image.ID, m Manifest) error {
+	sum, err := checksum(m.Functions)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(fileManifest{Manifest: m, Checksum: sum})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.root, 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.path(imageID), b, 0600)
+}
+
+func (s *store) Put(imageID image.ID, function string, chainID layer.ChainID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, err := s.get(imageID)
+	if err != nil {
+		return err
+	}
+	if m.Functions == nil {
+		m.Functions = map[string]layer.ChainID{}
+	}
+	m.Version = manifestVersion
+	m.Functions[function] = chainID
+	return s.save(imageID, m)
+}
+
+func (s *store) List(imageID image.ID) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, err := s.get(imageID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(m.Functions))
+	for name := range m.Functions {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (s *store) Delete(imageID image.ID, function string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, err := s.get(imageID)
+	if err != nil {
+		return err
+	}
+	if _, ok := m.Functions[function]; !ok {
+		return errdefs.NotFound(fmt.Errorf("function %q not found for image %s", function, imageID))
+	}
+	delete(m.Functions, function)
+	return s.save(imageID, m)
+}
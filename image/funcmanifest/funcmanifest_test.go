@@ -0,0 +1,80 @@
+package funcmanifest
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+)
+
+func TestStorePutGetListDelete(t *testing.T) {
+	s := NewStore(t.TempDir())
+	imageID := image.ID("sha256:deadbeef")
+
+	m, err := s.Get(imageID)
+	if err != nil {
+		t.Fatalf("Get on unwritten image: %v", err)
+	}
+	if len(m.Functions) != 0 {
+		t.Fatalf("expected empty manifest, got %v", m.Functions)
+	}
+
+	if err := s.Put(imageID, "resize", layer.ChainID("chain1")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := s.Put(imageID, "thumbnail", layer.ChainID("chain2")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	names, err := s.List(imageID)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(names) != 2 {
+		t.Fatalf("expected 2 functions, got %v", names)
+	}
+
+	m, err = s.Get(imageID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if m.Functions["resize"] != layer.ChainID("chain1") {
+		t.Fatalf("expected resize -> chain1, got %v", m.Functions)
+	}
+
+	if err := s.Delete(imageID, "resize"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	m, err = s.Get(imageID)
+	if err != nil {
+		t.Fatalf("Get after Delete: %v", err)
+	}
+	if _, ok := m.Functions["resize"]; ok {
+		t.Fatalf("expected resize to be deleted, still present in %v", m.Functions)
+	}
+
+	if err := s.Delete(imageID, "resize"); !errdefs.IsNotFound(err) {
+		t.Fatalf("expected errdefs.NotFound deleting an already-deleted function, got %v", err)
+	}
+}
+
+func TestStoreRejectsCorruptManifest(t *testing.T) {
+	root := t.TempDir()
+	s := NewStore(root)
+	imageID := image.ID("sha256:corrupt")
+
+	if err := s.Put(imageID, "resize", layer.ChainID("chain1")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	p := s.(*store).path(imageID)
+	if err := ioutil.WriteFile(p, []byte(`{"version":1,"functions":{"resize":"tampered"},"checksum":"bogus"}`), 0600); err != nil {
+		t.Fatalf("tampering with manifest file: %v", err)
+	}
+
+	if _, err := s.Get(imageID); err == nil {
+		t.Fatal("expected checksum mismatch error, got nil")
+	}
+}
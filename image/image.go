@@ -0,0 +1,34 @@
+package image
+
+import "github.com/docker/docker/layer"
+
+// ID is the content-addressable ID of an image.
+type ID string
+
+// String returns a string rendition of an ID.
+func (id ID) String() string {
+	return string(id)
+}
+
+// RootFS describes images root filesystem. This is currently a placeholder
+// that only holds the top layer's chain ID.
+type RootFS struct {
+	chainID layer.ChainID
+}
+
+// ChainID returns the ChainID of the image's top layer.
+func (r *RootFS) ChainID() layer.ChainID {
+	return r.chainID
+}
+
+// Image stores the image configuration.
+type Image struct {
+	RootFS *RootFS
+
+	id ID
+}
+
+// ID returns the image's content-addressable ID.
+func (img *Image) ID() ID {
+	return img.id
+}
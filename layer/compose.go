@@ -0,0 +1,122 @@
+package layer
+
+import "errors"
+
+// ErrNotSupported is returned by a Store's CreateComposedParent when the
+// underlying graph driver has no (yet) implementation for composing
+// function layers.
+var ErrNotSupported = errors.New("layer: composing function layers is not supported by this storage driver")
+
+// ComposeResult describes the outcome of composing a chain of function
+// layers on top of a base layer chain.
+type ComposeResult struct {
+	// BaseChainID is the chain ID the functions were stacked onto.
+	BaseChainID ChainID
+	// ComposedChain is the full list of layer IDs now making up the chain,
+	// ordered from the topmost function layer down to the base's own
+	// ancestors.
+	ComposedChain []ChainID
+	// ChainID is a new chain ID, owned by this compose and derived from
+	// containerID, that Store.CreateComposedParent materialized to
+	// reproduce BaseChainID with ComposedChain layered on top. Callers
+	// create the RWLayer on ChainID, never on BaseChainID directly: since
+	// ChainID is new and unique to this container, composing never
+	// touches anything a concurrent ContainerCreate for the same base
+	// image might be reading.
+	//
+	// ChainID outlives the ContainerCreate call that produced it: the
+	// container's RWLayer is mounted against it every time the container
+	// is subsequently started, not just once during create. It is only
+	// released (Restore) once the container itself is removed, or
+	// immediately if the create that requested it never finishes.
+	ChainID ChainID
+
+	// journalPath is the write-ahead record for this compose, cleared by
+	// whichever of Commit or Restore resolves it first.
+	journalPath string
+}
+
+// LayerComposer stacks a sequence of function layers on top of a base layer
+// chain, producing a parent chain a container's RWLayer can be created on
+// and mounted against for as long as the container exists. It drives the
+// graph driver's own composition primitive via Store rather than reaching
+// into any driver's on-disk files itself, so it needs no per-driver
+// implementation.
+type LayerComposer interface {
+	// Compose stacks chain, in order, on top of base and returns a new,
+	// composer-owned ChainID the caller can mount in its place. containerID
+	// seeds that ChainID and is recorded in the write-ahead journal, so a
+	// half-finished compose can be traced back to (and cleaned up for) the
+	// container that requested it.
+	Compose(containerID string, base ChainID, chain []ChainID) (*ComposeResult, error)
+
+	// Commit marks result as durably in use by the container it was
+	// composed for, clearing its crash-recovery journal entry without
+	// releasing the composed parent chain itself. Call it once the
+	// ContainerCreate that composed result has otherwise succeeded.
+	Commit(result *ComposeResult) error
+
+	// Restore releases the composed parent chain Compose created for
+	// result, clearing its journal entry in the process. Call it when the
+	// container that would have used result is never created (create
+	// failed after Compose succeeded), or later, when that container is
+	// removed.
+	Restore(result *ComposeResult) error
+}
+
+// composer is the sole LayerComposer implementation: it drives store's
+// graph-driver-backed CreateComposedParent/RemoveComposedParent rather than
+// composing on disk itself, so the same code serves every driver.
+type composer struct {
+	store Store
+}
+
+// NewLayerComposer returns a LayerComposer backed by store.
+func NewLayerComposer(store Store) LayerComposer {
+	return &composer{store: store}
+}
+
+// ComposedChainID returns the ChainID a compose for containerID was (or
+// would be) materialized under. Deterministic in containerID so a later
+// release, done from nothing but a container's ID, finds the same chain
+// Compose returned in its ComposeResult.
+func ComposedChainID(containerID string) ChainID {
+	return ChainID("compose-" + containerID)
+}
+
+func (c *composer) Compose(containerID string, base ChainID, chain []ChainID) (*ComposeResult, error) {
+	id := ComposedChainID(containerID)
+
+	journalP, err := writeJournal(c.store.DriverName(), containerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	composedID, err := c.store.CreateComposedParent(id.String(), base, chain)
+	if err != nil {
+		removeJournal(journalP)
+		return nil, err
+	}
+
+	composed := make([]ChainID, 0, len(chain)+1)
+	composed = append(composed, chain...)
+	composed = append(composed, base)
+
+	return &ComposeResult{
+		BaseChainID:   base,
+		ComposedChain: composed,
+		ChainID:       composedID,
+		journalPath:   journalP,
+	}, nil
+}
+
+func (c *composer) Commit(result *ComposeResult) error {
+	return removeJournal(result.journalPath)
+}
+
+func (c *composer) Restore(result *ComposeResult) error {
+	if err := c.store.RemoveComposedParent(result.ChainID); err != nil {
+		return err
+	}
+	return removeJournal(result.journalPath)
+}
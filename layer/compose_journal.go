@@ -0,0 +1,109 @@
+package layer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/docker/pkg/stringid"
+)
+
+// journalEntry is the write-ahead record a LayerComposer persists before it
+// asks the graph driver to materialize a composed parent chain. If the
+// daemon is killed, panics, or hits an error before the matching Commit or
+// Restore runs, ReplayJournal uses these records to release that chain on
+// the next startup.
+type journalEntry struct {
+	ComposedID  string    `json:"composed_id"`
+	ContainerID string    `json:"container_id"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// composeJournalRoot is the base directory compose journals are stored
+// under. It's a package variable, rather than a literal inlined into
+// journalDir, so tests can point it at a temporary directory instead of
+// the real host root.
+var composeJournalRoot = "/var/lib/docker/image"
+
+func journalDir(driverName string) string {
+	return path.Join(composeJournalRoot, driverName, "compose-journal")
+}
+
+// writeJournal records that composedID was (or is about to be) created on
+// behalf of containerID under driverName, and returns the path of the
+// journal record so the composer can remove it once Commit or Restore
+// completes, or once CreateComposedParent fails.
+func writeJournal(driverName, containerID string, composedID ChainID) (string, error) {
+	dir := journalDir(driverName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(journalEntry{
+		ComposedID:  composedID.String(),
+		ContainerID: containerID,
+		Timestamp:   time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	p := path.Join(dir, stringid.GenerateNonCryptoID())
+	return p, ioutil.WriteFile(p, b, 0600)
+}
+
+// removeJournal drops a journal record once its compose has been resolved:
+// Committed, Restored, or never materialized because CreateComposedParent
+// failed. An empty or already-removed path is not an error.
+func removeJournal(p string) error {
+	if p == "" {
+		return nil
+	}
+	err := os.Remove(p)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// ReplayJournal rolls back any compose left half-finished by a daemon that
+// was killed, panicked, or hit an error between asking store's driver to
+// materialize a composed parent chain and Committing or releasing it. A
+// Committed compose has no record left, so it is not touched. It must be
+// called once at daemon startup, after daemon.layerStore is initialized and
+// before any ContainerCreate is served.
+func ReplayJournal(store Store) error {
+	dir := journalDir(store.DriverName())
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		p := filepath.Join(dir, f.Name())
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		var entry journalEntry
+		if err := json.Unmarshal(b, &entry); err != nil {
+			return err
+		}
+
+		if err := store.RemoveComposedParent(ChainID(entry.ComposedID)); err != nil {
+			return err
+		}
+
+		if err := removeJournal(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package layer
+
+import (
+	"os"
+	"testing"
+)
+
+// fakeComposeStore is a minimal Store for exercising ReplayJournal without
+// a real graph driver backing it.
+type fakeComposeStore struct {
+	driverName string
+	removed    []ChainID
+}
+
+func (s *fakeComposeStore) DriverName() string { return s.driverName }
+
+func (s *fakeComposeStore) CreateRWLayer(id string, parent ChainID, mountLabel string, initFunc func(string) error) (RWLayer, error) {
+	return nil, nil
+}
+
+func (s *fakeComposeStore) CreateComposedParent(id string, base ChainID, chain []ChainID) (ChainID, error) {
+	return ChainID(id), nil
+}
+
+func (s *fakeComposeStore) RemoveComposedParent(id ChainID) error {
+	s.removed = append(s.removed, id)
+	return nil
+}
+
+func withTempJournalRoot(t *testing.T) func() {
+	t.Helper()
+	dir := t.TempDir()
+	orig := composeJournalRoot
+	composeJournalRoot = dir
+	return func() { composeJournalRoot = orig }
+}
+
+func TestReplayJournalRemovesHalfFinishedComposes(t *testing.T) {
+	defer withTempJournalRoot(t)()
+
+	store := &fakeComposeStore{driverName: "aufs"}
+
+	journalP, err := writeJournal(store.DriverName(), "container1", ChainID("compose-container1"))
+	if err != nil {
+		t.Fatalf("writeJournal: %v", err)
+	}
+
+	if err := ReplayJournal(store); err != nil {
+		t.Fatalf("ReplayJournal: %v", err)
+	}
+
+	if len(store.removed) != 1 || store.removed[0] != ChainID("compose-container1") {
+		t.Fatalf("expected compose-container1 to be removed, got %v", store.removed)
+	}
+
+	if _, err := os.Stat(journalP); !os.IsNotExist(err) {
+		t.Fatalf("expected journal record to be removed, stat err = %v", err)
+	}
+}
+
+func TestReplayJournalNoJournalDirIsNoop(t *testing.T) {
+	defer withTempJournalRoot(t)()
+
+	store := &fakeComposeStore{driverName: "overlay2"}
+
+	if err := ReplayJournal(store); err != nil {
+		t.Fatalf("ReplayJournal on empty journal dir: %v", err)
+	}
+	if len(store.removed) != 0 {
+		t.Fatalf("expected nothing removed, got %v", store.removed)
+	}
+}
+
+// TestReplayJournalIgnoresCommittedComposes guards the interaction between
+// Commit and crash recovery: once a compose has been Committed (the
+// container that requested it now exists and uses its composed parent
+// chain), a daemon restart must not treat it as half-finished and release
+// the chain out from under the running container.
+func TestReplayJournalIgnoresCommittedComposes(t *testing.T) {
+	defer withTempJournalRoot(t)()
+
+	store := &fakeComposeStore{driverName: "aufs"}
+	composer := NewLayerComposer(store)
+
+	result, err := composer.Compose("container1", ChainID("base"), []ChainID{"fn1"})
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+	if err := composer.Commit(result); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	if err := ReplayJournal(store); err != nil {
+		t.Fatalf("ReplayJournal: %v", err)
+	}
+	if len(store.removed) != 0 {
+		t.Fatalf("ReplayJournal must not release a committed compose's chain, got removed=%v", store.removed)
+	}
+}
@@ -0,0 +1,64 @@
+package layer
+
+import "testing"
+
+// TestComposeCommitKeepsComposedParentForContainerLifetime guards the bug
+// where a successfully created container's composed parent chain got
+// released the moment create() returned, instead of staying resolvable for
+// every later docker start. Commit must clear the journal entry without
+// touching the chain itself; only Restore releases it.
+func TestComposeCommitKeepsComposedParentForContainerLifetime(t *testing.T) {
+	defer withTempJournalRoot(t)()
+
+	store := &fakeComposeStore{driverName: "aufs"}
+	composer := NewLayerComposer(store)
+
+	result, err := composer.Compose("container1", ChainID("base"), []ChainID{"fn1"})
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+
+	// The container would start here, mounting its RWLayer against
+	// result.ChainID. Compose itself must not have released it already.
+	if len(store.removed) != 0 {
+		t.Fatalf("Compose must not release the composed parent, got removed=%v", store.removed)
+	}
+
+	if err := composer.Commit(result); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if len(store.removed) != 0 {
+		t.Fatalf("Commit must not release the composed parent, got removed=%v", store.removed)
+	}
+
+	// Only container removal releases it.
+	if err := composer.Restore(result); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if len(store.removed) != 1 || store.removed[0] != result.ChainID {
+		t.Fatalf("expected composed parent released on container removal, got removed=%v", store.removed)
+	}
+}
+
+// TestComposeRestoreOnFailedCreateReleasesImmediately covers the other
+// branch: if the ContainerCreate that requested a compose never succeeds,
+// no container is left to use the composed parent, so Restore (not Commit)
+// must release it right away.
+func TestComposeRestoreOnFailedCreateReleasesImmediately(t *testing.T) {
+	defer withTempJournalRoot(t)()
+
+	store := &fakeComposeStore{driverName: "aufs"}
+	composer := NewLayerComposer(store)
+
+	result, err := composer.Compose("container2", ChainID("base"), []ChainID{"fn1"})
+	if err != nil {
+		t.Fatalf("Compose: %v", err)
+	}
+
+	if err := composer.Restore(result); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if len(store.removed) != 1 || store.removed[0] != result.ChainID {
+		t.Fatalf("expected composed parent released after a failed create, got removed=%v", store.removed)
+	}
+}
@@ -0,0 +1,39 @@
+package layer
+
+// ChainID is the content-addressable ID of a layer.
+type ChainID string
+
+// String returns a string rendition of a ChainID.
+func (id ChainID) String() string {
+	return string(id)
+}
+
+// Store represents a backend for managing both
+// read-only and read-write layers.
+type Store interface {
+	// DriverName returns the name of the graph driver backing this store,
+	// e.g. "aufs" or "overlay2".
+	DriverName() string
+
+	CreateRWLayer(id string, parent ChainID, mountLabel string, initFunc func(string) error) (RWLayer, error)
+
+	// CreateComposedParent asks the graph driver to materialize, under id,
+	// a parent chain that reproduces base with chain layered on top of
+	// it — using the driver's own multi-parent primitive (AUFS's branch
+	// list, overlay2's lowerdir) rather than a caller parsing or
+	// rewriting base's own on-disk parent record. It returns the ChainID
+	// a later CreateRWLayer call can use as its parent. Drivers with no
+	// such primitive yet return ErrNotSupported.
+	CreateComposedParent(id string, base ChainID, chain []ChainID) (ChainID, error)
+
+	// RemoveComposedParent releases what CreateComposedParent allocated
+	// under id. It is a no-op if id was never created, so crash recovery
+	// can call it unconditionally for a compose that might not have
+	// reached CreateComposedParent yet.
+	RemoveComposedParent(id ChainID) error
+}
+
+// RWLayer is a read-write layer that may be mounted for a running container.
+type RWLayer interface {
+	Mount(mountLabel string) (string, error)
+}
@@ -0,0 +1,44 @@
+package opts
+
+import (
+	"fmt"
+
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/engine-api/types"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+// addComposeFunctionFlag registers the --function flag shared by `docker
+// create` and `docker run`. It is kept separate from the container.Config
+// flags because ComposeFunctions lives on types.ContainerCreateConfig, not
+// on the container config itself.
+func addComposeFunctionFlag(cmd *flag.FlagSet) *[]string {
+	return cmd.StringSlice([]string{"-function"}, []string{}, "Compose the named function layers onto the image, in order")
+}
+
+// setComposeFunctions copies the parsed --function values onto the create
+// config that will be sent to the daemon.
+func setComposeFunctions(config *types.ContainerCreateConfig, functions *[]string) {
+	config.ComposeFunctions = *functions
+}
+
+// Parse parses the flags and positional arguments shared by `docker create`
+// and `docker run` into a ContainerCreateConfig ready to send to the
+// daemon. cmd must not have been parsed yet.
+func Parse(cmd *flag.FlagSet, args []string) (*types.ContainerCreateConfig, error) {
+	functions := addComposeFunctionFlag(cmd)
+
+	if err := cmd.ParseFlags(args, true); err != nil {
+		return nil, err
+	}
+	if cmd.NArg() == 0 {
+		return nil, fmt.Errorf("IMAGE name required")
+	}
+
+	config := &types.ContainerCreateConfig{
+		Config: &containertypes.Config{Image: cmd.Arg(0)},
+	}
+	setComposeFunctions(config, functions)
+
+	return config, nil
+}
@@ -0,0 +1,7 @@
+package container
+
+// Config contains the configuration data about a container.
+// It should hold only portable information about the container.
+type Config struct {
+	Image string // Name of the image as it was passed by the operator (e.g. could be symbolic)
+}
@@ -0,0 +1,31 @@
+package container
+
+import "strings"
+
+// IpcMode represents the container ipc stack.
+type IpcMode string
+
+// IsHost indicates whether the container shares the host's ipc namespace.
+func (n IpcMode) IsHost() bool {
+	return n == "host"
+}
+
+// Container returns the name of the container ipc stack is going to be used.
+func (n IpcMode) Container() string {
+	parts := strings.SplitN(string(n), ":", 2)
+	if len(parts) > 1 {
+		return parts[1]
+	}
+	return ""
+}
+
+// PidMode represents the pid namespace of the container.
+type PidMode string
+
+// IsHost indicates whether the container shares the host's pid namespace.
+func (n PidMode) IsHost() bool {
+	return n == "host"
+}
+
+// HostConfig the non-portable Config structure of a container.
+type HostConfig struct{}
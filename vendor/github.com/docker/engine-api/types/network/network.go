@@ -0,0 +1,12 @@
+package network
+
+// NetworkingConfig represents the container's networking configuration for
+// each of its interfaces.
+type NetworkingConfig struct {
+	EndpointsConfig map[string]*EndpointSettings // Endpoint configs for each connecting network
+}
+
+// EndpointSettings stores the network endpoint details
+type EndpointSettings struct {
+	NetworkID string
+}